@@ -0,0 +1,75 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed pricing.yaml
+var defaultPricingCatalog []byte
+
+// RegionPricing is the per-region pricing used to price a resolved Fargate
+// pod configuration. ArmDiscount is the fractional discount Graviton
+// (linux/arm64) gets off the standard x86 rate (e.g. 0.20 = 20% cheaper).
+type RegionPricing struct {
+	FargateCPUHour    float64 `yaml:"fargateCpuHour" json:"fargateCpuHour"`
+	FargateMemoryHour float64 `yaml:"fargateMemoryHour" json:"fargateMemoryHour"`
+	ArmDiscount       float64 `yaml:"armDiscount" json:"armDiscount"`
+	WindowsCPUHour    float64 `yaml:"windowsCpuHour" json:"windowsCpuHour"`
+	WindowsMemoryHour float64 `yaml:"windowsMemoryHour" json:"windowsMemoryHour"`
+}
+
+// PricingCatalog is a region-keyed pricing table, loaded from the embedded
+// default or a --pricing-file override. The file format is YAML (a superset
+// of JSON), so a plain JSON file works without any extra handling.
+type PricingCatalog map[string]RegionPricing
+
+func loadPricingCatalog(path string) (PricingCatalog, error) {
+	data := defaultPricingCatalog
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading pricing file %s: %w", path, err)
+		}
+	}
+
+	var catalog PricingCatalog
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("parsing pricing catalog: %w", err)
+	}
+	return catalog, nil
+}
+
+// pricingCatalog lazily loads and caches the pricing catalog for cfg.
+func (cfg *Config) pricingCatalog() (PricingCatalog, error) {
+	cfg.catalogOnce.Do(func() {
+		cfg.catalog, cfg.catalogErr = loadPricingCatalog(cfg.PricingFile)
+	})
+	return cfg.catalog, cfg.catalogErr
+}
+
+// effectivePricing returns cfg.Region's catalog entry, with --fargate-cpu-hour
+// and --fargate-memory-hour applied as explicit overrides when set (they
+// default to 0, meaning "use the catalog").
+func (cfg *Config) effectivePricing() (RegionPricing, error) {
+	catalog, err := cfg.pricingCatalog()
+	if err != nil {
+		return RegionPricing{}, err
+	}
+	pricing, ok := catalog[cfg.Region]
+	if !ok {
+		return RegionPricing{}, fmt.Errorf("no pricing entry for region %q", cfg.Region)
+	}
+
+	if cfg.FargateCPUHour > 0 {
+		pricing.FargateCPUHour = cfg.FargateCPUHour
+	}
+	if cfg.FargateMemoryHour > 0 {
+		pricing.FargateMemoryHour = cfg.FargateMemoryHour
+	}
+	return pricing, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Config holds the pricing and filtering options shared by every command.
+// It is embedded by each Kong subcommand so `report` and `serve` resolve
+// Fargate/EC2 costs identically.
+type Config struct {
+	Namespace          string             `name:"namespace" help:"Namespace selector (optional)" default:""`
+	UseRequestsOnly    bool               `name:"use-requests-only" help:"If set to true, calculator will only use requests and not limits." default:"false"`
+	AssumeOptimization bool               `name:"assume-request-optimization" help:"Enabling this option will make calculator expect that requests would be adjusted down to meet Fargate pod config values." default:"false"`
+	FargateCPUHour     float64            `name:"fargate-cpu-hour" help:"Override the catalog's Fargate CPU price per hour for --region. 0 (default) uses the catalog." default:"0"`
+	FargateMemoryHour  float64            `name:"fargate-memory-hour" help:"Override the catalog's Fargate memory price per hour for --region. 0 (default) uses the catalog." default:"0"`
+	Ec2InstanceHour    map[string]float64 `name:"ec2-instance-hour" help:"Hourly on-demand prices of instance types (comma-separated), e.g. c5.xlarge=0.194" default:"c5.xlarge=0.194"`
+	Ec2SpotHour        map[string]float64 `name:"ec2-spot-hour" help:"Hourly spot prices of instance types (comma-separated), e.g. c5.xlarge=0.0683" default:""`
+	AwsRegion          string             `name:"aws-region" help:"If set, load current EC2 spot prices from the DescribeSpotPriceHistory API for this region instead of relying solely on --ec2-spot-hour." default:""`
+	ExcludeDaemonSets  bool               `name:"exclude-daemonsets" help:"Exclude Pods owned by DaemonSets (as not supported in Fargate)." default:"true"`
+	ExcludeIstioProxy  bool               `name:"exclude-istio-proxy" help:"Exclude istio-proxy containers (as not supported in Fargate)." default:"true"`
+
+	KubeReservedCPU      string `name:"kube-reserved-cpu" help:"CPU reserved for kubelet/container-runtime components, mirrors kubelet's --kube-reserved cpu key. Fargate bills roughly 1 vCPU of agent headroom per pod." default:"1000m"`
+	KubeReservedMemory   string `name:"kube-reserved-memory" help:"Memory reserved for kubelet/container-runtime components, mirrors kubelet's --kube-reserved memory key. Leave empty to derive it from the standard EKS formula (255Mi + 11Mi * max-pods), which reproduces AWS's documented ~256Mi Fargate per-pod overhead when max-pods is 1." default:""`
+	SystemReservedCPU    string `name:"system-reserved-cpu" help:"CPU reserved for OS system daemons, mirrors kubelet's --system-reserved cpu key." default:"0m"`
+	SystemReservedMemory string `name:"system-reserved-memory" help:"Memory reserved for OS system daemons, mirrors kubelet's --system-reserved memory key." default:"0Mi"`
+	EvictionThreshold    string `name:"eviction-threshold" help:"Memory held back for kubelet's --eviction-hard memory.available threshold. Defaults to 0 since Fargate has no analogous per-pod eviction headroom; set it explicitly to model a cluster that reserves one." default:"0Mi"`
+
+	Region      string `name:"region" help:"AWS region to price against in the pricing catalog." default:"us-east-1"`
+	PricingFile string `name:"pricing-file" help:"Path to a pricing catalog file (YAML or JSON) overriding the embedded defaults; same schema, keyed by region." default:""`
+	FargateArch string `name:"fargate-arch" enum:"auto,amd64,arm64" help:"Force the Fargate CPU architecture profile instead of detecting it from each pod's nodeSelector/tolerations." default:"auto"`
+	FargateOS   string `name:"fargate-os" enum:"auto,linux,windows" help:"Force the Fargate OS profile instead of detecting it from each pod's nodeSelector/tolerations." default:"auto"`
+
+	catalogOnce sync.Once
+	catalog     PricingCatalog
+	catalogErr  error
+}
+
+// shouldSkipPod reports whether pod should be excluded from cost accounting
+// entirely (as opposed to simply having some containers excluded).
+func (cfg *Config) shouldSkipPod(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return true
+	}
+	if cfg.ExcludeDaemonSets {
+		for _, owner := range pod.OwnerReferences {
+			if owner.Kind == "DaemonSet" {
+				log.Debugf("Skipping DaemonSet Pod %s/%s.", pod.Namespace, pod.Name)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// podResourceUsage resolves the effective CPU/memory for pod (containers,
+// init containers and pod-level resources per effectivePodResourceUsage),
+// then applies the kube-reserved/system-reserved/eviction-hard overhead
+// model and, if enabled, the request-optimization adjustment.
+func (cfg *Config) podResourceUsage(pod *corev1.Pod) (cpu, memory resource.Quantity, err error) {
+	cpu, memory = cfg.effectivePodResourceUsage(pod)
+
+	overheadCPU, overheadMemory, err := cfg.podOverhead()
+	if err != nil {
+		return cpu, memory, err
+	}
+	cpu.Add(overheadCPU)
+	memory.Add(overheadMemory)
+
+	if cfg.AssumeOptimization {
+		if cpu.ScaledValue(resource.Milli) > 1500 {
+			cpu.Sub(*resource.NewScaledQuantity(1000, resource.Milli))
+		} else if cpu.ScaledValue(resource.Milli) > 750 {
+			cpu.Sub(*resource.NewScaledQuantity(500, resource.Milli))
+		} else {
+			cpu.Sub(*resource.NewScaledQuantity(250, resource.Milli))
+		}
+		if memory.ScaledValue(resource.Mega) > 1536 {
+			memory.Sub(*resource.NewScaledQuantity(1024, resource.Mega))
+		} else {
+			memory.Sub(*resource.NewScaledQuantity(512, resource.Mega))
+		}
+	}
+
+	return cpu, memory, nil
+}
+
+// resolveFargatePrice selects pod's Fargate profile (Linux/x86, Graviton or
+// Windows), matches cpu/memory against that profile's available
+// configurations and returns the smallest one that fits, along with its
+// hourly price. matched is false if no configuration is large enough.
+func (cfg *Config) resolveFargatePrice(pod *corev1.Pod, cpu, memory resource.Quantity) (cpuMillis, memMega int64, price float64, matched bool, err error) {
+	pricing, err := cfg.effectivePricing()
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+
+	profile := cfg.selectFargateProfile(pod)
+	megaPerMillis := profile.MegaPerMillis()
+	for _, cpuOption := range profile.Millis() {
+		if !(cpu.IsZero() || cpuOption >= cpu.ScaledValue(resource.Milli)) {
+			continue
+		}
+		for _, memoryOption := range megaPerMillis[cpuOption] {
+			if memoryOption >= memory.ScaledValue(resource.Mega) {
+				price = profile.HourlyPrice(pricing, cpuOption, memoryOption)
+				return cpuOption, memoryOption, price, true, nil
+			}
+		}
+	}
+	return 0, 0, 0, false, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed instances.yaml
+var defaultInstanceCatalog []byte
+
+// InstanceSpec is the vCPU/memory capacity and ENI-derived max pod count for
+// one EC2 instance type, used by the `simulate` bin-packing command.
+type InstanceSpec struct {
+	VCPU       int64 `yaml:"vcpu"`
+	MemoryMega int64 `yaml:"memoryMega"`
+	MaxPods    int64 `yaml:"maxPods"`
+}
+
+// InstanceCatalog is an instance-type-keyed capacity table.
+type InstanceCatalog map[string]InstanceSpec
+
+var (
+	instanceCatalogOnce sync.Once
+	instanceCatalog     InstanceCatalog
+	instanceCatalogErr  error
+)
+
+// loadInstanceCatalog lazily parses the embedded instance capacity catalog.
+func loadInstanceCatalog() (InstanceCatalog, error) {
+	instanceCatalogOnce.Do(func() {
+		instanceCatalogErr = yaml.Unmarshal(defaultInstanceCatalog, &instanceCatalog)
+	})
+	return instanceCatalog, instanceCatalogErr
+}
+
+// instanceSpecs resolves InstanceSpec for each of instanceTypes, in order,
+// erroring out on the first type missing from the catalog.
+func instanceSpecs(instanceTypes []string) ([]InstanceSpec, error) {
+	catalog, err := loadInstanceCatalog()
+	if err != nil {
+		return nil, fmt.Errorf("loading instance catalog: %w", err)
+	}
+
+	specs := make([]InstanceSpec, 0, len(instanceTypes))
+	for _, instanceType := range instanceTypes {
+		spec, ok := catalog[instanceType]
+		if !ok {
+			return nil, fmt.Errorf("unknown instance type %q, not present in the instance capacity catalog", instanceType)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
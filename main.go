@@ -1,12 +1,8 @@
 package main
 
 import (
-	"context"
 	"github.com/alecthomas/kong"
 	log "github.com/sirupsen/logrus"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"os"
@@ -14,7 +10,8 @@ import (
 )
 
 func main() {
-	ctx := kong.Parse(&Interface{},
+	cli := CLI{}
+	ctx := kong.Parse(&cli,
 		kong.Name("fargate-calculator"),
 		kong.Description("Calculate Fargate cost for Kubernetes workload."),
 		kong.UsageOnError(),
@@ -23,149 +20,22 @@ func main() {
 			Summary: false,
 		}))
 
-	err := ctx.Run()
-	ctx.FatalIfErrorf(err)
-}
-
-type Interface struct {
-	Namespace          string             `name:"namespace" help:"Namespace selector (optional)" default:""`
-	UseRequestsOnly    bool               `name:"use-requests-only" help:"If set to true, calculator will only use requests and not limits." default:"false"`
-	AssumeOptimization bool               `name:"assume-request-optimization" help:"Enabling this option will make calculator expect that requests would be adjusted down to meet Fargate pod config values." default:"false"`
-	FargateCPUHour     float64            `name:"fargate-cpu-hour" help:"Price of Fargate CPU per Hour" default:"0.04656"`
-	FargateMemoryHour  float64            `name:"fargate-memory-hour" help:"Price of Fargate Memory per Hour" default:"0.00511"`
-	Ec2InstanceHour    map[string]float64 `name:"ec2-instance-hour" help:"Hourly prices of instance types (comma-separated), e.g. c5.xlarge=0.194" default:"c5.xlarge=0.194"`
-	ExcludeDaemonSets  bool               `name:"exclude-daemonsets" help:"Exclude Pods owned by DaemonSets (as not supported in Fargate)." default:"true"`
-	ExcludeIstioProxy  bool               `name:"exclude-istio-proxy" help:"Exclude istio-proxy containers (as not supported in Fargate)." default:"true"`
-	Debug              bool               `name:"debug" help:"Enable debug logging."`
-}
-
-func (cmd *Interface) Run() error {
-	if cmd.Debug {
+	if cli.Debug {
 		log.SetLevel(log.DebugLevel)
 	}
-	ctx := context.TODO()
-
-	clientSet, err := getClientSet()
-	podList, err := clientSet.CoreV1().Pods(cmd.Namespace).List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return err
-	}
-	log.Debugf("Found %v pods.", len(podList.Items))
-
-	var fargateMegaPerMillis = getFargateMegaPerMillis()
-
-	var fargateTotalCpu int64
-	var fargateTotalMemory int64
-	var fargateTotalPrice float64
-	for _, pod := range podList.Items {
-		if pod.Status.Phase == corev1.PodSucceeded && pod.Status.Phase == corev1.PodFailed {
-			continue
-		}
-		if cmd.ExcludeDaemonSets {
-			var isDaemonSetPod = false
-			for _, owner := range pod.OwnerReferences {
-				if owner.Kind == "DaemonSet" {
-					isDaemonSetPod = true
-				}
-			}
-			if isDaemonSetPod {
-				log.Debugf("Skipping DaemonSet Pod %s/%s.", pod.Namespace, pod.Name)
-				continue
-			}
-		}
-
-		var podCpu, podMemory resource.Quantity
-		for _, container := range pod.Spec.Containers {
-			if cmd.ExcludeIstioProxy && container.Name == "istio-proxy" {
-				continue
-			}
-			if container.Resources.Limits.Cpu().IsZero() || cmd.UseRequestsOnly {
-				if !container.Resources.Requests.Cpu().IsZero() {
-					podCpu.Add(*container.Resources.Requests.Cpu())
-				}
-			} else {
-				podCpu.Add(*container.Resources.Limits.Cpu())
-			}
-
-			if container.Resources.Limits.Memory().IsZero() || cmd.UseRequestsOnly {
-				if !container.Resources.Requests.Memory().IsZero() {
-					podMemory.Add(*container.Resources.Requests.Memory())
-				}
-			} else {
-				podMemory.Add(*container.Resources.Limits.Memory())
-			}
-		}
 
-		podMemory.Add(*resource.NewScaledQuantity(250, resource.Mega))
-		//log.Debugf("Caluclated pod %s/%s with %vm CPU and %vMi memory.", pod.Namespace, pod.Name, podCpu.ScaledValue(resource.Milli), podMemory.ScaledValue(resource.Mega))
-
-		if cmd.AssumeOptimization {
-			if podCpu.ScaledValue(resource.Milli) > 1500 {
-				podCpu.Sub(*resource.NewScaledQuantity(1000, resource.Milli))
-			} else if podCpu.ScaledValue(resource.Milli) > 750 {
-				podCpu.Sub(*resource.NewScaledQuantity(500, resource.Milli))
-			} else {
-				podCpu.Sub(*resource.NewScaledQuantity(250, resource.Milli))
-			}
-			if podMemory.ScaledValue(resource.Mega) > 1536 {
-				podMemory.Sub(*resource.NewScaledQuantity(1024, resource.Mega))
-			} else {
-				podMemory.Sub(*resource.NewScaledQuantity(512, resource.Mega))
-			}
-		}
-
-		var match = false
-		for _, cpuOption := range getFargateMillis() {
-			if podCpu.IsZero() || cpuOption >= podCpu.ScaledValue(resource.Milli) {
-				for _, memoryOption := range fargateMegaPerMillis[cpuOption] {
-					if memoryOption >= podMemory.ScaledValue(resource.Mega) {
-						match = true
-						var fargatePrice = (float64(cpuOption) / 1000 * cmd.FargateCPUHour) + (float64(memoryOption) / 1024 * cmd.FargateMemoryHour)
-						log.Infof("Resolved Fargate configuration %v CPU and %v Memory for Pod %s/%s (%vm / %vMi) with hourly price: %v$", float64(cpuOption)/1000, float64(memoryOption)/1024, pod.Namespace, pod.Name, podCpu.ScaledValue(resource.Milli), podMemory.ScaledValue(resource.Mega), fargatePrice)
-						fargateTotalCpu += cpuOption
-						fargateTotalMemory += memoryOption
-						fargateTotalPrice += fargatePrice
-						break
-					}
-				}
-				if match {
-					break
-				}
-			}
-		}
-		if !match {
-			log.Warnf("Did not match a fargate config for pod %s/%s with cpu %vm and memory %vMi.", pod.Namespace, pod.Name, podCpu.ScaledValue(resource.Milli), podMemory.ScaledValue(resource.Mega))
-		}
-	}
-
-	log.Infof("Total Fargate price/h for pods: %f", fargateTotalPrice)
-
-	nodeList, err := clientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return err
-	}
-	log.Debugf("Found %v nodes.", len(nodeList.Items))
-
-	var ec2Price float64
+	err := ctx.Run()
+	ctx.FatalIfErrorf(err)
+}
 
-	var fargateEquivalent float64
-	for _, node := range nodeList.Items {
-		if instanceType, ok := node.Labels["node.kubernetes.io/instance-type"]; ok {
-			if price, ok := cmd.Ec2InstanceHour[instanceType]; ok {
-				ec2Price = ec2Price + price
-			} else {
-				log.Warnf("EC2 price for %s not provided.", instanceType)
-			}
-		} else {
-			log.Warnf("Cannot determine instance type for node %s", node.Name)
-		}
-		fargateEquivalent += float64(node.Status.Allocatable.Cpu().ScaledValue(resource.Milli)) / 1000 * cmd.FargateCPUHour
-		fargateEquivalent += float64(node.Status.Allocatable.Memory().ScaledValue(resource.Mega)) / 1024 * cmd.FargateMemoryHour
-	}
+// CLI is the root Kong command. Report runs once and prints a summary;
+// Serve runs as a long-lived Prometheus exporter.
+type CLI struct {
+	Report   ReportCmd   `cmd:"" default:"1" help:"Calculate Fargate cost once and print a summary."`
+	Serve    ServeCmd    `cmd:"" help:"Run as a Prometheus exporter exposing the same costs as gauges."`
+	Simulate SimulateCmd `cmd:"" help:"Bin-pack the cluster's pods onto candidate EC2 instance types and compare."`
 
-	log.Infof("Total EC2 price/h for nodes: %v", ec2Price)
-	log.Infof("Fargate price/h for equivalent allocatable ressources: %v", fargateEquivalent)
-	return nil
+	Debug bool `name:"debug" help:"Enable debug logging."`
 }
 
 func getFargateMillis() []int64 {
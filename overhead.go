@@ -0,0 +1,77 @@
+package main
+
+import "k8s.io/apimachinery/pkg/api/resource"
+
+// podOverhead returns the CPU/memory this tool adds on top of a pod's own
+// container requests to approximate the kubelet/container-runtime overhead
+// Fargate bills for alongside the pod itself. It mirrors kubelet's
+// --kube-reserved/--system-reserved/--eviction-hard flags: kube-reserved
+// memory defaults to the standard EKS formula (255Mi + 11Mi * max-pods)
+// evaluated with max-pods=1, since a Fargate pod is effectively a
+// single-pod node — this reproduces AWS's documented ~256Mi per-pod
+// overhead without hard-coding it.
+func (cfg *Config) podOverhead() (cpu, memory resource.Quantity, err error) {
+	return cfg.reservedOverhead(1)
+}
+
+// nodeOverhead is the EC2-node equivalent of podOverhead: the hourly cost of
+// billing a node's allocatable resources at Fargate rates is only
+// apples-to-apples with the summed per-pod Fargate cost if it carries the
+// same per-pod overhead that many separate Fargate pods would — not a
+// single node-level reservation — so this applies podOverhead() once per
+// pod actually hosted on the node, rather than scaling a shared kube-reserved
+// figure by the node's pod capacity.
+func (cfg *Config) nodeOverhead(podCount int64) (cpu, memory resource.Quantity, err error) {
+	podCPU, podMemory, err := cfg.podOverhead()
+	if err != nil {
+		return cpu, memory, err
+	}
+	cpu = *resource.NewMilliQuantity(podCPU.MilliValue()*podCount, resource.DecimalSI)
+	memory = *resource.NewQuantity(podMemory.Value()*podCount, resource.BinarySI)
+	return cpu, memory, nil
+}
+
+func (cfg *Config) reservedOverhead(maxPods int64) (cpu, memory resource.Quantity, err error) {
+	kubeReservedCPU, err := resource.ParseQuantity(cfg.KubeReservedCPU)
+	if err != nil {
+		return cpu, memory, err
+	}
+	systemReservedCPU, err := resource.ParseQuantity(cfg.SystemReservedCPU)
+	if err != nil {
+		return cpu, memory, err
+	}
+	systemReservedMemory, err := resource.ParseQuantity(cfg.SystemReservedMemory)
+	if err != nil {
+		return cpu, memory, err
+	}
+	evictionThreshold, err := resource.ParseQuantity(cfg.EvictionThreshold)
+	if err != nil {
+		return cpu, memory, err
+	}
+	kubeReservedMemory, err := cfg.kubeReservedMemory(maxPods)
+	if err != nil {
+		return cpu, memory, err
+	}
+
+	cpu.Add(kubeReservedCPU)
+	cpu.Add(systemReservedCPU)
+	memory.Add(kubeReservedMemory)
+	memory.Add(systemReservedMemory)
+	memory.Add(evictionThreshold)
+	return cpu, memory, nil
+}
+
+// kubeReservedMemory returns the explicit --kube-reserved-memory override if
+// set, otherwise the standard EKS kube-reserved formula for a node that can
+// schedule maxPods pods: 255MiB + 11MiB per pod. These are binary MiB, so the
+// quantity is built from bytes rather than resource.Mega (decimal 10^6),
+// which would under-reserve by about 5%.
+func (cfg *Config) kubeReservedMemory(maxPods int64) (resource.Quantity, error) {
+	if cfg.KubeReservedMemory != "" {
+		return resource.ParseQuantity(cfg.KubeReservedMemory)
+	}
+	const mib = 1024 * 1024
+	reserved := resource.NewQuantity(255*mib, resource.BinarySI)
+	reserved.Add(*resource.NewQuantity(11*maxPods*mib, resource.BinarySI))
+	return *reserved, nil
+}
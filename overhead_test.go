@@ -0,0 +1,113 @@
+package main
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestReservedOverhead(t *testing.T) {
+	cases := []struct {
+		name       string
+		cfg        Config
+		maxPods    int64
+		wantCpu    string
+		wantMemory string
+	}{
+		{
+			name: "defaults at maxPods=1 match AWS's documented ~256Mi/1vCPU Fargate overhead",
+			cfg: Config{
+				KubeReservedCPU:      "1000m",
+				SystemReservedCPU:    "0m",
+				SystemReservedMemory: "0Mi",
+				EvictionThreshold:    "0Mi",
+			},
+			maxPods:    1,
+			wantCpu:    "1",
+			wantMemory: "266Mi",
+		},
+		{
+			name: "kube-reserved memory scales with maxPods",
+			cfg: Config{
+				KubeReservedCPU:      "1000m",
+				SystemReservedCPU:    "0m",
+				SystemReservedMemory: "0Mi",
+				EvictionThreshold:    "0Mi",
+			},
+			maxPods:    30,
+			wantCpu:    "1",
+			wantMemory: "585Mi",
+		},
+		{
+			name: "explicit kube-reserved-memory override bypasses the formula",
+			cfg: Config{
+				KubeReservedCPU:      "500m",
+				KubeReservedMemory:   "1Gi",
+				SystemReservedCPU:    "0m",
+				SystemReservedMemory: "0Mi",
+				EvictionThreshold:    "0Mi",
+			},
+			maxPods:    30,
+			wantCpu:    "500m",
+			wantMemory: "1Gi",
+		},
+		{
+			name: "system-reserved and eviction-threshold are additive",
+			cfg: Config{
+				KubeReservedCPU:      "1000m",
+				SystemReservedCPU:    "100m",
+				SystemReservedMemory: "64Mi",
+				EvictionThreshold:    "100Mi",
+			},
+			maxPods:    1,
+			wantCpu:    "1100m",
+			wantMemory: "430Mi",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cpu, memory, err := c.cfg.reservedOverhead(c.maxPods)
+			if err != nil {
+				t.Fatalf("reservedOverhead() error = %v", err)
+			}
+			wantCpu := resource.MustParse(c.wantCpu)
+			wantMemory := resource.MustParse(c.wantMemory)
+			if cpu.Cmp(wantCpu) != 0 {
+				t.Errorf("cpu = %v, want %v", cpu.String(), c.wantCpu)
+			}
+			if memory.Cmp(wantMemory) != 0 {
+				t.Errorf("memory = %v, want %v", memory.String(), c.wantMemory)
+			}
+		})
+	}
+}
+
+func TestNodeOverheadScalesPodOverheadByPodCount(t *testing.T) {
+	cfg := Config{
+		KubeReservedCPU:      "1000m",
+		SystemReservedCPU:    "0m",
+		SystemReservedMemory: "0Mi",
+		EvictionThreshold:    "0Mi",
+	}
+
+	podCpu, podMemory, err := cfg.podOverhead()
+	if err != nil {
+		t.Fatalf("podOverhead() error = %v", err)
+	}
+
+	const podCount = 12
+	nodeCpu, nodeMemory, err := cfg.nodeOverhead(podCount)
+	if err != nil {
+		t.Fatalf("nodeOverhead() error = %v", err)
+	}
+
+	wantCpu := resource.NewMilliQuantity(podCpu.MilliValue()*podCount, resource.DecimalSI)
+	wantMemory := resource.NewQuantity(podMemory.Value()*podCount, resource.BinarySI)
+	if nodeCpu.Cmp(*wantCpu) != 0 {
+		t.Errorf("nodeOverhead cpu = %v, want %v", nodeCpu.String(), wantCpu.String())
+	}
+	if nodeMemory.Cmp(*wantMemory) != 0 {
+		t.Errorf("nodeOverhead memory = %v, want %v", nodeMemory.String(), wantMemory.String())
+	}
+}
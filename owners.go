@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// workloadOwner identifies the top-level controller a Pod belongs to, e.g.
+// a Deployment, StatefulSet or CronJob. Pods without a controlling owner
+// report themselves as the owner.
+type workloadOwner struct {
+	Kind string
+	Name string
+}
+
+// ownerResolver walks OwnerReferences up through ReplicaSets and Jobs to
+// find the top-level workload that owns a Pod, caching intermediate lookups
+// so pods that share a ReplicaSet or Job only fetch it once.
+type ownerResolver struct {
+	ctx       context.Context
+	clientSet *kubernetes.Clientset
+	cache     map[string]workloadOwner
+}
+
+func newOwnerResolver(ctx context.Context, clientSet *kubernetes.Clientset) *ownerResolver {
+	return &ownerResolver{ctx: ctx, clientSet: clientSet, cache: map[string]workloadOwner{}}
+}
+
+func ownerCacheKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// resolve returns the top-level workload owning pod.
+func (r *ownerResolver) resolve(pod *corev1.Pod) (workloadOwner, error) {
+	controller := controllerRef(pod.OwnerReferences)
+	if controller == nil {
+		return workloadOwner{Kind: "Pod", Name: pod.Name}, nil
+	}
+	return r.resolveRef(pod.Namespace, controller.Kind, controller.Name)
+}
+
+func (r *ownerResolver) resolveRef(namespace, kind, name string) (workloadOwner, error) {
+	key := ownerCacheKey(kind, namespace, name)
+	if owner, ok := r.cache[key]; ok {
+		return owner, nil
+	}
+
+	var owner workloadOwner
+	switch kind {
+	case "ReplicaSet":
+		rs, err := r.clientSet.AppsV1().ReplicaSets(namespace).Get(r.ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return workloadOwner{}, fmt.Errorf("getting ReplicaSet %s/%s: %w", namespace, name, err)
+		}
+		if controller := controllerRef(rs.OwnerReferences); controller != nil {
+			owner, err = r.resolveRef(namespace, controller.Kind, controller.Name)
+			if err != nil {
+				return workloadOwner{}, err
+			}
+		} else {
+			owner = workloadOwner{Kind: "ReplicaSet", Name: name}
+		}
+	case "Job":
+		job, err := r.clientSet.BatchV1().Jobs(namespace).Get(r.ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return workloadOwner{}, fmt.Errorf("getting Job %s/%s: %w", namespace, name, err)
+		}
+		if controller := controllerRef(job.OwnerReferences); controller != nil {
+			owner, err = r.resolveRef(namespace, controller.Kind, controller.Name)
+			if err != nil {
+				return workloadOwner{}, err
+			}
+		} else {
+			owner = workloadOwner{Kind: "Job", Name: name}
+		}
+	default:
+		owner = workloadOwner{Kind: kind, Name: name}
+	}
+
+	r.cache[key] = owner
+	return owner, nil
+}
+
+// controllerRef returns the owner reference marking the managing controller,
+// or nil if none of refs is one.
+func controllerRef(refs []metav1.OwnerReference) *metav1.OwnerReference {
+	for i := range refs {
+		if refs[i].Controller != nil && *refs[i].Controller {
+			return &refs[i]
+		}
+	}
+	return nil
+}
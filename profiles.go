@@ -0,0 +1,110 @@
+package main
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// FargateProfile is the vCPU/memory configuration table and pricing formula
+// for one Fargate flavor. AWS prices Linux/x86, Linux/ARM64 (Graviton) and
+// Windows differently and Windows supports fewer pod-config combinations, so
+// the calculator resolves the applicable profile per pod rather than
+// assuming Linux/x86 for everything.
+type FargateProfile interface {
+	Name() string
+	Millis() []int64
+	MegaPerMillis() map[int64][]int64
+	HourlyPrice(pricing RegionPricing, cpuMillis, memMega int64) float64
+}
+
+type linuxAmd64Profile struct{}
+
+func (linuxAmd64Profile) Name() string                     { return "linux/amd64" }
+func (linuxAmd64Profile) Millis() []int64                  { return getFargateMillis() }
+func (linuxAmd64Profile) MegaPerMillis() map[int64][]int64 { return getFargateMegaPerMillis() }
+func (linuxAmd64Profile) HourlyPrice(pricing RegionPricing, cpuMillis, memMega int64) float64 {
+	return float64(cpuMillis)/1000*pricing.FargateCPUHour + float64(memMega)/1024*pricing.FargateMemoryHour
+}
+
+// linuxArm64Profile covers Graviton-backed Fargate pods, which use the same
+// configuration table as x86 but at a discounted rate.
+type linuxArm64Profile struct{}
+
+func (linuxArm64Profile) Name() string                     { return "linux/arm64" }
+func (linuxArm64Profile) Millis() []int64                  { return getFargateMillis() }
+func (linuxArm64Profile) MegaPerMillis() map[int64][]int64 { return getFargateMegaPerMillis() }
+func (linuxArm64Profile) HourlyPrice(pricing RegionPricing, cpuMillis, memMega int64) float64 {
+	onDemand := float64(cpuMillis)/1000*pricing.FargateCPUHour + float64(memMega)/1024*pricing.FargateMemoryHour
+	return onDemand * (1 - pricing.ArmDiscount)
+}
+
+// windowsProfile covers Windows Fargate pods, which top out at 4 vCPU / 16GB
+// and are billed at a separate, higher per-unit rate.
+type windowsProfile struct{}
+
+func (windowsProfile) Name() string { return "windows/amd64" }
+
+func (windowsProfile) Millis() []int64 {
+	return []int64{250, 500, 1000, 2000, 4000}
+}
+
+func (windowsProfile) MegaPerMillis() map[int64][]int64 {
+	result := map[int64][]int64{
+		250:  {512, 1024, 2048},
+		500:  {1024, 2048, 3072, 4096},
+		1000: make([]int64, 0),
+		2000: make([]int64, 0),
+		4000: make([]int64, 0),
+	}
+	for i := 2; i <= 8; i++ {
+		result[1000] = append(result[1000], int64(i*1024))
+	}
+	for i := 4; i <= 16; i++ {
+		result[2000] = append(result[2000], int64(i*1024))
+	}
+	for i := 8; i <= 16; i++ {
+		result[4000] = append(result[4000], int64(i*1024))
+	}
+	return result
+}
+
+func (windowsProfile) HourlyPrice(pricing RegionPricing, cpuMillis, memMega int64) float64 {
+	return float64(cpuMillis)/1000*pricing.WindowsCPUHour + float64(memMega)/1024*pricing.WindowsMemoryHour
+}
+
+// selectFargateProfile resolves the Fargate flavor for pod: --fargate-arch
+// and --fargate-os force a choice, otherwise it is detected from the pod's
+// kubernetes.io/arch and kubernetes.io/os nodeSelector/tolerations, falling
+// back to linux/amd64 when neither is present.
+func (cfg *Config) selectFargateProfile(pod *corev1.Pod) FargateProfile {
+	arch := cfg.FargateArch
+	if arch == "" || arch == "auto" {
+		arch = podPlacementValue(pod, "kubernetes.io/arch")
+	}
+	os := cfg.FargateOS
+	if os == "" || os == "auto" {
+		os = podPlacementValue(pod, "kubernetes.io/os")
+	}
+
+	if os == "windows" {
+		return windowsProfile{}
+	}
+	if arch == "arm64" {
+		return linuxArm64Profile{}
+	}
+	return linuxAmd64Profile{}
+}
+
+// podPlacementValue looks up label on the pod's nodeSelector, falling back
+// to any toleration keyed on it (the common way pods target Windows/ARM
+// nodes via their taints).
+func podPlacementValue(pod *corev1.Pod, label string) string {
+	if value, ok := pod.Spec.NodeSelector[label]; ok {
+		return value
+	}
+	for _, toleration := range pod.Spec.Tolerations {
+		if toleration.Key == label && toleration.Value != "" {
+			return toleration.Value
+		}
+	}
+	return ""
+}
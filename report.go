@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReportCmd is the default command: list pods/nodes once and print the
+// aggregated Fargate and EC2 costs.
+type ReportCmd struct {
+	Config
+
+	GroupBy string `name:"group-by" enum:"pod,owner,namespace" default:"pod" help:"Aggregate pod costs per pod, per owning workload, or per namespace."`
+}
+
+// podCost is the resolved Fargate cost for a single pod, along with the
+// top-level workload that owns it.
+type podCost struct {
+	Namespace string
+	PodName   string
+	Owner     workloadOwner
+	CpuMillis int64
+	MemMega   int64
+	Price     float64
+}
+
+func (cmd *ReportCmd) Run() error {
+	ctx := context.TODO()
+
+	clientSet, err := getClientSet()
+	if err != nil {
+		return err
+	}
+	podList, err := clientSet.CoreV1().Pods(cmd.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	log.Debugf("Found %v pods.", len(podList.Items))
+
+	owners := newOwnerResolver(ctx, clientSet)
+
+	var fargateTotalCpu int64
+	var fargateTotalMemory int64
+	var fargateTotalPrice float64
+	var costs []podCost
+	for _, pod := range podList.Items {
+		if cmd.shouldSkipPod(&pod) {
+			continue
+		}
+
+		podCpu, podMemory, err := cmd.podResourceUsage(&pod)
+		if err != nil {
+			return fmt.Errorf("computing resource usage for pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+
+		cpuOption, memoryOption, fargatePrice, matched, err := cmd.resolveFargatePrice(&pod, podCpu, podMemory)
+		if err != nil {
+			return fmt.Errorf("resolving fargate price for pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		if !matched {
+			log.Warnf("Did not match a fargate config for pod %s/%s with cpu %vm and memory %vMi.", pod.Namespace, pod.Name, podCpu.ScaledValue(resource.Milli), podMemory.ScaledValue(resource.Mega))
+			continue
+		}
+		log.Infof("Resolved Fargate configuration %v CPU and %v Memory for Pod %s/%s (%vm / %vMi) with hourly price: %v$", float64(cpuOption)/1000, float64(memoryOption)/1024, pod.Namespace, pod.Name, podCpu.ScaledValue(resource.Milli), podMemory.ScaledValue(resource.Mega), fargatePrice)
+		fargateTotalCpu += cpuOption
+		fargateTotalMemory += memoryOption
+		fargateTotalPrice += fargatePrice
+
+		owner := workloadOwner{Kind: "Pod", Name: pod.Name}
+		if cmd.GroupBy != "pod" {
+			resolved, err := owners.resolve(&pod)
+			if err != nil {
+				log.Warnf("Could not resolve owner for pod %s/%s, treating it as its own workload: %v", pod.Namespace, pod.Name, err)
+			} else {
+				owner = resolved
+			}
+		}
+		costs = append(costs, podCost{
+			Namespace: pod.Namespace,
+			PodName:   pod.Name,
+			Owner:     owner,
+			CpuMillis: cpuOption,
+			MemMega:   memoryOption,
+			Price:     fargatePrice,
+		})
+	}
+
+	cmd.printSummary(costs)
+
+	log.Infof("Total Fargate price/h for pods: %f", fargateTotalPrice)
+
+	nodeList, err := clientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	log.Debugf("Found %v nodes.", len(nodeList.Items))
+
+	spotPrices := newSpotPriceCache()
+	if cmd.AwsRegion != "" {
+		if err := spotPrices.loadSpotPriceHistory(ctx, cmd.AwsRegion); err != nil {
+			log.Warnf("Could not load spot prices from AWS region %s, falling back to --ec2-spot-hour: %v", cmd.AwsRegion, err)
+		}
+	}
+
+	nodePodCounts := map[string]int64{}
+	for _, pod := range podList.Items {
+		if cmd.shouldSkipPod(&pod) || pod.Spec.NodeName == "" {
+			continue
+		}
+		nodePodCounts[pod.Spec.NodeName]++
+	}
+
+	var ec2OnDemandPrice float64
+	var ec2SpotPrice float64
+
+	var fargateEquivalent float64
+	for _, node := range nodeList.Items {
+		if instanceType, ok := node.Labels["node.kubernetes.io/instance-type"]; ok {
+			if isSpotNode(node) {
+				az := node.Labels["topology.kubernetes.io/zone"]
+				if price, ok := spotPrices.price(instanceType, az); ok {
+					ec2SpotPrice += price
+				} else if price, ok := cmd.Ec2SpotHour[instanceType]; ok {
+					ec2SpotPrice += price
+				} else {
+					log.Warnf("EC2 spot price for %s not provided.", instanceType)
+				}
+			} else {
+				if price, ok := cmd.Ec2InstanceHour[instanceType]; ok {
+					ec2OnDemandPrice += price
+				} else {
+					log.Warnf("EC2 on-demand price for %s not provided.", instanceType)
+				}
+			}
+		} else {
+			log.Warnf("Cannot determine instance type for node %s", node.Name)
+		}
+
+		overheadCPU, overheadMemory, err := cmd.nodeOverhead(nodePodCounts[node.Name])
+		if err != nil {
+			return fmt.Errorf("computing node overhead for %s: %w", node.Name, err)
+		}
+		nodeCpuMillis := node.Status.Allocatable.Cpu().ScaledValue(resource.Milli) + overheadCPU.ScaledValue(resource.Milli)
+		nodeMemMega := node.Status.Allocatable.Memory().ScaledValue(resource.Mega) + overheadMemory.ScaledValue(resource.Mega)
+		pricing, err := cmd.effectivePricing()
+		if err != nil {
+			return fmt.Errorf("resolving fargate pricing: %w", err)
+		}
+		fargateEquivalent += float64(nodeCpuMillis) / 1000 * pricing.FargateCPUHour
+		fargateEquivalent += float64(nodeMemMega) / 1024 * pricing.FargateMemoryHour
+	}
+
+	log.Infof("Total EC2 price/h for on-demand nodes: %v", ec2OnDemandPrice)
+	log.Infof("Total EC2 price/h for spot nodes: %v", ec2SpotPrice)
+	log.Infof("Total EC2 price/h for nodes: %v", ec2OnDemandPrice+ec2SpotPrice)
+	log.Infof("Fargate price/h for equivalent allocatable ressources: %v", fargateEquivalent)
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// effectivePodResourceUsage computes the container-level CPU/memory a pod
+// consumes, following Kubernetes' own effective-request semantics:
+//   - pod-level spec.resources (1.32+) override container sums entirely
+//   - otherwise it is the peak concurrent usage across the pod's lifecycle
+//     per containerGroupUsage, which accounts for restartPolicy:Always
+//     sidecar init containers staying up alongside later init containers
+//     and the app phase
+//   - spec.overhead (RuntimeClass sandbox overhead, e.g. Kata/gVisor) is
+//     always added on top
+func (cfg *Config) effectivePodResourceUsage(pod *corev1.Pod) (cpu, memory resource.Quantity) {
+	if pod.Spec.Resources != nil {
+		cpu, memory = cfg.resourceListUsage(*pod.Spec.Resources)
+	} else {
+		cpu, memory = cfg.containerGroupUsage(pod)
+	}
+
+	cpu.Add(*pod.Spec.Overhead.Cpu())
+	memory.Add(*pod.Spec.Overhead.Memory())
+	return cpu, memory
+}
+
+// resourceListUsage applies the requests-vs-limits precedence (honoring
+// --use-requests-only) to a single ResourceRequirements block.
+func (cfg *Config) resourceListUsage(r corev1.ResourceRequirements) (cpu, memory resource.Quantity) {
+	if r.Limits.Cpu().IsZero() || cfg.UseRequestsOnly {
+		cpu = *r.Requests.Cpu()
+	} else {
+		cpu = *r.Limits.Cpu()
+	}
+	if r.Limits.Memory().IsZero() || cfg.UseRequestsOnly {
+		memory = *r.Requests.Memory()
+	} else {
+		memory = *r.Limits.Memory()
+	}
+	return cpu, memory
+}
+
+// containerGroupUsage sums app and ephemeral containers, then walks init
+// containers in order to find the pod's peak concurrent usage.
+// restartPolicy:Always sidecar init containers start in init order and,
+// once started, keep running through the rest of init and the app phase
+// (kubernetes.io/enhancements KEP-753), so a plain init container's
+// footprint is its own request plus whatever sidecars had already started
+// before it, and the app phase's footprint is the app containers plus every
+// sidecar. The result is the largest of those points, not just
+// max(all-sidecars, largest-single-init).
+func (cfg *Config) containerGroupUsage(pod *corev1.Pod) (cpu, memory resource.Quantity) {
+	var appCpu, appMemory resource.Quantity
+	for _, container := range pod.Spec.Containers {
+		if cfg.ExcludeIstioProxy && container.Name == "istio-proxy" {
+			continue
+		}
+		c, m := cfg.resourceListUsage(container.Resources)
+		appCpu.Add(c)
+		appMemory.Add(m)
+	}
+	for _, ephemeralContainer := range pod.Spec.EphemeralContainers {
+		c, m := cfg.resourceListUsage(ephemeralContainer.Resources)
+		appCpu.Add(c)
+		appMemory.Add(m)
+	}
+
+	peakCpu := appCpu.DeepCopy()
+	peakMemory := appMemory.DeepCopy()
+	var runningSidecarCpu, runningSidecarMemory resource.Quantity
+	for _, initContainer := range pod.Spec.InitContainers {
+		c, m := cfg.resourceListUsage(initContainer.Resources)
+		if initContainer.RestartPolicy != nil && *initContainer.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			runningSidecarCpu.Add(c)
+			runningSidecarMemory.Add(m)
+			continue
+		}
+		candidateCpu := runningSidecarCpu.DeepCopy()
+		candidateCpu.Add(c)
+		candidateMemory := runningSidecarMemory.DeepCopy()
+		candidateMemory.Add(m)
+		if candidateCpu.Cmp(peakCpu) > 0 {
+			peakCpu = candidateCpu
+		}
+		if candidateMemory.Cmp(peakMemory) > 0 {
+			peakMemory = candidateMemory
+		}
+	}
+
+	finalCpu := appCpu.DeepCopy()
+	finalCpu.Add(runningSidecarCpu)
+	finalMemory := appMemory.DeepCopy()
+	finalMemory.Add(runningSidecarMemory)
+	if finalCpu.Cmp(peakCpu) > 0 {
+		peakCpu = finalCpu
+	}
+	if finalMemory.Cmp(peakMemory) > 0 {
+		peakMemory = finalMemory
+	}
+	return peakCpu, peakMemory
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func resources(cpu, memory string) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		},
+	}
+}
+
+func TestEffectivePodResourceUsage(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+
+	cases := []struct {
+		name       string
+		pod        corev1.Pod
+		wantCpu    string
+		wantMemory string
+	}{
+		{
+			name: "app containers only",
+			pod: corev1.Pod{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app", Resources: resources("250m", "256Mi")}},
+			}},
+			wantCpu:    "250m",
+			wantMemory: "256Mi",
+		},
+		{
+			name: "heavy plain init dominates a light app container",
+			pod: corev1.Pod{Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init", Resources: resources("2", "2Gi")}},
+				Containers:     []corev1.Container{{Name: "app", Resources: resources("250m", "256Mi")}},
+			}},
+			wantCpu:    "2",
+			wantMemory: "2Gi",
+		},
+		{
+			name: "running sidecar plus plain init run concurrently",
+			pod: corev1.Pod{Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{
+					{Name: "istio-init", RestartPolicy: &always, Resources: resources("100m", "128Mi")},
+					{Name: "device-plugin-init", Resources: resources("2", "2Gi")},
+				},
+				Containers: []corev1.Container{{Name: "app", Resources: resources("250m", "256Mi")}},
+			}},
+			// The heavy plain init runs while the sidecar is already up, so
+			// the peak is the sidecar's request plus the init's, not just
+			// the larger of the two.
+			wantCpu:    "2100m",
+			wantMemory: "2176Mi",
+		},
+		{
+			name: "app phase plus sidecar exceeds any init-phase peak",
+			pod: corev1.Pod{Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{
+					{Name: "istio-init", RestartPolicy: &always, Resources: resources("500m", "512Mi")},
+					{Name: "init", Resources: resources("100m", "128Mi")},
+				},
+				Containers: []corev1.Container{{Name: "app", Resources: resources("1", "1Gi")}},
+			}},
+			wantCpu:    "1500m",
+			wantMemory: "1536Mi",
+		},
+	}
+
+	cfg := &Config{}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cpu, memory := cfg.effectivePodResourceUsage(&c.pod)
+			want := resources(c.wantCpu, c.wantMemory)
+			if cpu.Cmp(*want.Requests.Cpu()) != 0 {
+				t.Errorf("cpu = %v, want %v", cpu.String(), c.wantCpu)
+			}
+			if memory.Cmp(*want.Requests.Memory()) != 0 {
+				t.Errorf("memory = %v, want %v", memory.String(), c.wantMemory)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ServeCmd runs the calculator as a long-lived Prometheus exporter, watching
+// pods and nodes via informers and keeping gauges in sync instead of
+// printing a one-shot summary.
+type ServeCmd struct {
+	Config
+
+	Listen string `name:"listen" help:"Address to serve /metrics on." default:":8080"`
+}
+
+var (
+	podHourlyCostGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fargate_pod_hourly_cost",
+		Help: "Hourly Fargate cost of the resolved pod configuration, in USD.",
+	}, []string{"namespace", "pod", "workload"})
+
+	podVcpuGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fargate_pod_vcpu",
+		Help: "Resolved Fargate vCPU configuration for the pod.",
+	}, []string{"namespace", "pod", "workload"})
+
+	podMemoryGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fargate_pod_memory_gb",
+		Help: "Resolved Fargate memory configuration for the pod, in GB.",
+	}, []string{"namespace", "pod", "workload"})
+
+	nodeHourlyCostGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ec2_node_hourly_cost",
+		Help: "Hourly EC2 cost of the node, in USD.",
+	}, []string{"node", "instance_type", "lifecycle"})
+
+	clusterFargateEquivalentGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "cluster_fargate_equivalent_hourly_cost",
+		Help: "Hourly cost if every node's allocatable resources were billed at Fargate rates.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(podHourlyCostGauge, podVcpuGauge, podMemoryGauge, nodeHourlyCostGauge, clusterFargateEquivalentGauge)
+}
+
+func (cmd *ServeCmd) Run() error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	clientSet, err := getClientSet()
+	if err != nil {
+		return err
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(clientSet, time.Minute, informers.WithNamespace(cmd.Namespace))
+	podInformer := factory.Core().V1().Pods().Informer()
+	nodeInformer := factory.Core().V1().Nodes().Informer()
+
+	spotPrices := newSpotPriceCache()
+	if cmd.AwsRegion != "" {
+		if err := spotPrices.loadSpotPriceHistory(ctx, cmd.AwsRegion); err != nil {
+			log.Warnf("Could not load spot prices from AWS region %s, falling back to --ec2-spot-hour: %v", cmd.AwsRegion, err)
+		}
+	}
+
+	owners := newOwnerResolver(ctx, clientSet)
+
+	podHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { cmd.syncPod(obj, owners, nodeInformer, podInformer) },
+		UpdateFunc: func(_, obj interface{}) { cmd.syncPod(obj, owners, nodeInformer, podInformer) },
+		DeleteFunc: func(obj interface{}) { cmd.removePod(obj, owners, nodeInformer, podInformer) },
+	}
+	nodeHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { cmd.syncNode(obj, spotPrices, nodeInformer, podInformer) },
+		UpdateFunc: func(_, obj interface{}) { cmd.syncNode(obj, spotPrices, nodeInformer, podInformer) },
+		DeleteFunc: func(obj interface{}) { cmd.removeNode(obj, nodeInformer, podInformer) },
+	}
+	if _, err := podInformer.AddEventHandler(podHandler); err != nil {
+		return err
+	}
+	if _, err := nodeInformer.AddEventHandler(nodeHandler); err != nil {
+		return err
+	}
+
+	factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), podInformer.HasSynced, nodeInformer.HasSynced) {
+		return errors.New("timed out waiting for informer caches to sync")
+	}
+	log.Infof("Informer caches synced, serving metrics on %s/metrics.", cmd.Listen)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Addr: cmd.Listen, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+func (cmd *ServeCmd) syncPod(obj interface{}, owners *ownerResolver, nodeInformer, podInformer cache.SharedIndexInformer) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	if cmd.shouldSkipPod(pod) {
+		cmd.removePod(pod, owners, nodeInformer, podInformer)
+		return
+	}
+
+	podCpu, podMemory, err := cmd.podResourceUsage(pod)
+	if err != nil {
+		log.Warnf("Could not compute resource usage for pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		cmd.removePod(pod, owners, nodeInformer, podInformer)
+		return
+	}
+	cpuMillis, memMega, price, matched, err := cmd.resolveFargatePrice(pod, podCpu, podMemory)
+	if err != nil {
+		log.Warnf("Could not resolve fargate price for pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		cmd.removePod(pod, owners, nodeInformer, podInformer)
+		return
+	}
+	if !matched {
+		log.Warnf("Did not match a fargate config for pod %s/%s.", pod.Namespace, pod.Name)
+		cmd.removePod(pod, owners, nodeInformer, podInformer)
+		return
+	}
+
+	labels := prometheus.Labels{"namespace": pod.Namespace, "pod": pod.Name, "workload": workloadLabel(pod, owners)}
+	podHourlyCostGauge.With(labels).Set(price)
+	podVcpuGauge.With(labels).Set(float64(cpuMillis) / 1000)
+	podMemoryGauge.With(labels).Set(float64(memMega) / 1024)
+
+	cmd.recalculateClusterFargateEquivalent(nodeInformer, podInformer)
+}
+
+func (cmd *ServeCmd) removePod(obj interface{}, owners *ownerResolver, nodeInformer, podInformer cache.SharedIndexInformer) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			pod, ok = tombstone.Obj.(*corev1.Pod)
+			if !ok {
+				return
+			}
+		} else {
+			return
+		}
+	}
+	labels := prometheus.Labels{"namespace": pod.Namespace, "pod": pod.Name, "workload": workloadLabel(pod, owners)}
+	podHourlyCostGauge.Delete(labels)
+	podVcpuGauge.Delete(labels)
+	podMemoryGauge.Delete(labels)
+
+	cmd.recalculateClusterFargateEquivalent(nodeInformer, podInformer)
+}
+
+// workloadLabel resolves the top-level workload owning pod, falling back to
+// the pod's own name if the owner chain cannot be resolved.
+func workloadLabel(pod *corev1.Pod, owners *ownerResolver) string {
+	owner, err := owners.resolve(pod)
+	if err != nil {
+		log.Warnf("Could not resolve owner for pod %s/%s, using pod name as workload: %v", pod.Namespace, pod.Name, err)
+		return pod.Name
+	}
+	return owner.Name
+}
+
+func (cmd *ServeCmd) syncNode(obj interface{}, spotPrices *spotPriceCache, nodeInformer, podInformer cache.SharedIndexInformer) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return
+	}
+
+	instanceType := node.Labels["node.kubernetes.io/instance-type"]
+	lifecycle := "on-demand"
+	var price float64
+	if isSpotNode(*node) {
+		lifecycle = "spot"
+		az := node.Labels["topology.kubernetes.io/zone"]
+		if p, ok := spotPrices.price(instanceType, az); ok {
+			price = p
+		} else if p, ok := cmd.Ec2SpotHour[instanceType]; ok {
+			price = p
+		} else {
+			log.Warnf("EC2 spot price for %s not provided.", instanceType)
+		}
+	} else {
+		if p, ok := cmd.Ec2InstanceHour[instanceType]; ok {
+			price = p
+		} else {
+			log.Warnf("EC2 on-demand price for %s not provided.", instanceType)
+		}
+	}
+	nodeHourlyCostGauge.With(prometheus.Labels{"node": node.Name, "instance_type": instanceType, "lifecycle": lifecycle}).Set(price)
+
+	cmd.recalculateClusterFargateEquivalent(nodeInformer, podInformer)
+}
+
+func (cmd *ServeCmd) removeNode(obj interface{}, nodeInformer, podInformer cache.SharedIndexInformer) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			node, ok = tombstone.Obj.(*corev1.Node)
+			if !ok {
+				return
+			}
+		} else {
+			return
+		}
+	}
+	nodeHourlyCostGauge.DeletePartialMatch(prometheus.Labels{"node": node.Name})
+	cmd.recalculateClusterFargateEquivalent(nodeInformer, podInformer)
+}
+
+// recalculateClusterFargateEquivalent sums the allocatable resources of
+// every node currently in the informer cache and re-derives the hourly cost
+// of billing that capacity at Fargate rates, mirroring the `report`
+// command's fargateEquivalent computation. The per-node overhead is scaled
+// by the pods actually hosted on it (per nodeOverhead), so this stays
+// apples-to-apples with the summed per-pod Fargate cost instead of adding a
+// single node-level reservation.
+func (cmd *ServeCmd) recalculateClusterFargateEquivalent(nodeInformer, podInformer cache.SharedIndexInformer) {
+	nodePodCounts := map[string]int64{}
+	for _, obj := range podInformer.GetStore().List() {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || cmd.shouldSkipPod(pod) || pod.Spec.NodeName == "" {
+			continue
+		}
+		nodePodCounts[pod.Spec.NodeName]++
+	}
+
+	var fargateEquivalent float64
+	for _, obj := range nodeInformer.GetStore().List() {
+		node, ok := obj.(*corev1.Node)
+		if !ok {
+			continue
+		}
+		overheadCPU, overheadMemory, err := cmd.nodeOverhead(nodePodCounts[node.Name])
+		if err != nil {
+			log.Warnf("Could not compute node overhead for %s: %v", node.Name, err)
+			continue
+		}
+		nodeCpuMillis := node.Status.Allocatable.Cpu().ScaledValue(resource.Milli) + overheadCPU.ScaledValue(resource.Milli)
+		nodeMemMega := node.Status.Allocatable.Memory().ScaledValue(resource.Mega) + overheadMemory.ScaledValue(resource.Mega)
+		pricing, err := cmd.effectivePricing()
+		if err != nil {
+			log.Warnf("Could not resolve fargate pricing: %v", err)
+			continue
+		}
+		fargateEquivalent += float64(nodeCpuMillis) / 1000 * pricing.FargateCPUHour
+		fargateEquivalent += float64(nodeMemMega) / 1024 * pricing.FargateMemoryHour
+	}
+	clusterFargateEquivalentGauge.Set(fargateEquivalent)
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SimulateCmd bin-packs the cluster's current pods onto a hypothetical fleet
+// of EC2 instance types, so "what would this look like on self-managed EC2
+// instead of Fargate" can be compared on a packing-efficiency aware basis
+// rather than against today's actual node shapes.
+type SimulateCmd struct {
+	Config
+
+	CandidateInstances []string `name:"candidate-instances" help:"Comma-separated EC2 instance types to bin-pack pods onto, e.g. m6i.large,m6i.xlarge,c6i.2xlarge." required:""`
+}
+
+// podDemand is the bin-packing input for one pod: its resolved CPU/memory
+// footprint, independent of any Fargate-specific overhead or pricing.
+type podDemand struct {
+	Namespace string
+	PodName   string
+	CpuMillis int64
+	MemMega   int64
+}
+
+// candidateInstance pairs an instance type's capacity with its on-demand
+// hourly price.
+type candidateInstance struct {
+	Type         string
+	Spec         InstanceSpec
+	PricePerHour float64
+}
+
+// simulatedNode is one opened bin: an instance of a candidateInstance with
+// pods packed onto it so far.
+type simulatedNode struct {
+	Candidate     candidateInstance
+	PodCount      int64
+	UsedCpuMillis int64
+	UsedMemMega   int64
+}
+
+func (n *simulatedNode) fits(d podDemand) bool {
+	return n.PodCount+1 <= n.Candidate.Spec.MaxPods &&
+		n.UsedCpuMillis+d.CpuMillis <= n.Candidate.Spec.VCPU*1000 &&
+		n.UsedMemMega+d.MemMega <= n.Candidate.Spec.MemoryMega
+}
+
+func (n *simulatedNode) place(d podDemand) {
+	n.PodCount++
+	n.UsedCpuMillis += d.CpuMillis
+	n.UsedMemMega += d.MemMega
+}
+
+// remainingFraction is a smaller-is-tighter-fit measure, used to pick the
+// best-fit open node among those a pod fits in (summed remaining cpu/memory
+// capacity as a fraction of the node's total).
+func (n *simulatedNode) remainingFraction() float64 {
+	cpuFrac := float64(n.Candidate.Spec.VCPU*1000-n.UsedCpuMillis) / float64(n.Candidate.Spec.VCPU*1000)
+	memFrac := float64(n.Candidate.Spec.MemoryMega-n.UsedMemMega) / float64(n.Candidate.Spec.MemoryMega)
+	return cpuFrac + memFrac
+}
+
+func (cmd *SimulateCmd) Run() error {
+	candidates, err := cmd.resolveCandidates()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	clientSet, err := getClientSet()
+	if err != nil {
+		return err
+	}
+	podList, err := clientSet.CoreV1().Pods(cmd.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	log.Debugf("Found %v pods.", len(podList.Items))
+
+	var demands []podDemand
+	for _, pod := range podList.Items {
+		if cmd.shouldSkipPod(&pod) {
+			continue
+		}
+		cpu, memory := cmd.effectivePodResourceUsage(&pod)
+		demands = append(demands, podDemand{
+			Namespace: pod.Namespace,
+			PodName:   pod.Name,
+			CpuMillis: cpu.ScaledValue(resource.Milli),
+			MemMega:   memory.ScaledValue(resource.Mega),
+		})
+	}
+
+	// First-Fit-Decreasing: pack the pods most likely to dominate a node's
+	// capacity first, so they get first pick of a fresh bin instead of
+	// fragmenting whatever small pods happened to be packed earlier.
+	smallest := candidates[0]
+	sort.Slice(demands, func(i, j int) bool {
+		return demandRatio(demands[i], smallest) > demandRatio(demands[j], smallest)
+	})
+
+	nodes, unplaceable := packPods(demands, candidates)
+	for _, d := range unplaceable {
+		log.Warnf("Pod %s/%s (%vm / %vMi) does not fit on any candidate instance type.", d.Namespace, d.PodName, d.CpuMillis, d.MemMega)
+	}
+
+	cmd.printSimulation(nodes, len(unplaceable))
+	return nil
+}
+
+// resolveCandidates looks up capacity specs and --ec2-instance-hour prices
+// for every --candidate-instances entry, sorted smallest-capacity first so
+// Run can use the first entry as the reference for ordering pod demands.
+// packPods does not depend on this order: it opens nodes cheapest-first.
+func (cmd *SimulateCmd) resolveCandidates() ([]candidateInstance, error) {
+	if len(cmd.CandidateInstances) == 0 {
+		return nil, errors.New("--candidate-instances is required, e.g. --candidate-instances=m6i.large,m6i.xlarge,c6i.2xlarge")
+	}
+
+	specs, err := instanceSpecs(cmd.CandidateInstances)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]candidateInstance, len(cmd.CandidateInstances))
+	for i, instanceType := range cmd.CandidateInstances {
+		price, ok := cmd.Ec2InstanceHour[instanceType]
+		if !ok {
+			return nil, fmt.Errorf("no --ec2-instance-hour price provided for candidate instance type %s", instanceType)
+		}
+		candidates[i] = candidateInstance{Type: instanceType, Spec: specs[i], PricePerHour: price}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Spec.VCPU != candidates[j].Spec.VCPU {
+			return candidates[i].Spec.VCPU < candidates[j].Spec.VCPU
+		}
+		return candidates[i].Spec.MemoryMega < candidates[j].Spec.MemoryMega
+	})
+	return candidates, nil
+}
+
+// demandRatio is how much of reference's capacity d would use on its own,
+// taking whichever of cpu/memory is more constrained.
+func demandRatio(d podDemand, reference candidateInstance) float64 {
+	cpuRatio := float64(d.CpuMillis) / float64(reference.Spec.VCPU*1000)
+	memRatio := float64(d.MemMega) / float64(reference.Spec.MemoryMega)
+	if cpuRatio > memRatio {
+		return cpuRatio
+	}
+	return memRatio
+}
+
+// packPods bin-packs demands (assumed pre-sorted, largest first) onto
+// candidates using First-Fit-Decreasing with a best-fit choice among open
+// nodes: each pod goes on the tightest-fit
+// already-open node it fits on, or else opens the cheapest candidate type
+// that can host it alone. Pods too large for every candidate are returned
+// as unplaceable rather than dropped silently.
+func packPods(demands []podDemand, candidates []candidateInstance) (nodes []*simulatedNode, unplaceable []podDemand) {
+	cheapestFirst := make([]candidateInstance, len(candidates))
+	copy(cheapestFirst, candidates)
+	sort.Slice(cheapestFirst, func(i, j int) bool { return cheapestFirst[i].PricePerHour < cheapestFirst[j].PricePerHour })
+
+	for _, d := range demands {
+		var best *simulatedNode
+		for _, node := range nodes {
+			if !node.fits(d) {
+				continue
+			}
+			if best == nil || node.remainingFraction() < best.remainingFraction() {
+				best = node
+			}
+		}
+		if best != nil {
+			best.place(d)
+			continue
+		}
+
+		var opened *candidateInstance
+		for i := range cheapestFirst {
+			c := cheapestFirst[i]
+			if d.CpuMillis <= c.Spec.VCPU*1000 && d.MemMega <= c.Spec.MemoryMega && c.Spec.MaxPods >= 1 {
+				opened = &cheapestFirst[i]
+				break
+			}
+		}
+		if opened == nil {
+			unplaceable = append(unplaceable, d)
+			continue
+		}
+		node := &simulatedNode{Candidate: *opened}
+		node.place(d)
+		nodes = append(nodes, node)
+	}
+	return nodes, unplaceable
+}
+
+// printSimulation logs a per-instance-type node count/cost table plus the
+// overall packing efficiency, the same way printSummary logs report's
+// per-workload table.
+func (cmd *SimulateCmd) printSimulation(nodes []*simulatedNode, unplaceableCount int) {
+	counts := map[string]int{}
+	var order []string
+	var totalCost float64
+	var totalCpuUsed, totalCpuCapacity, totalMemUsed, totalMemCapacity int64
+	for _, node := range nodes {
+		if _, ok := counts[node.Candidate.Type]; !ok {
+			order = append(order, node.Candidate.Type)
+		}
+		counts[node.Candidate.Type]++
+		totalCost += node.Candidate.PricePerHour
+		totalCpuUsed += node.UsedCpuMillis
+		totalCpuCapacity += node.Candidate.Spec.VCPU * 1000
+		totalMemUsed += node.UsedMemMega
+		totalMemCapacity += node.Candidate.Spec.MemoryMega
+	}
+	sort.Strings(order)
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w, "INSTANCE TYPE\tNODES\tHOURLY COST")
+	for _, instanceType := range order {
+		fmt.Fprintf(w, "%s\t%d\t%v$\n", instanceType, counts[instanceType], float64(counts[instanceType])*cmd.pricePerHour(nodes, instanceType))
+	}
+	w.Flush()
+	log.Infof("Simulated node fleet:\n%s", buf.String())
+
+	log.Infof("Total nodes: %d", len(nodes))
+	log.Infof("Total EC2 price/h for simulated fleet: %v", totalCost)
+	if totalCpuCapacity > 0 && totalMemCapacity > 0 {
+		log.Infof("Packing efficiency: cpu %.1f%%, memory %.1f%%", 100*float64(totalCpuUsed)/float64(totalCpuCapacity), 100*float64(totalMemUsed)/float64(totalMemCapacity))
+	}
+	if unplaceableCount > 0 {
+		log.Warnf("%d pod(s) did not fit on any candidate instance type and were excluded from the simulation.", unplaceableCount)
+	}
+}
+
+// pricePerHour returns the hourly price of instanceType from nodes, used to
+// render the per-type cost column without a second candidate lookup table.
+func (cmd *SimulateCmd) pricePerHour(nodes []*simulatedNode, instanceType string) float64 {
+	for _, node := range nodes {
+		if node.Candidate.Type == instanceType {
+			return node.Candidate.PricePerHour
+		}
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package main
+
+import "testing"
+
+func smallCandidate() candidateInstance {
+	return candidateInstance{Type: "small", Spec: InstanceSpec{VCPU: 2, MemoryMega: 4096, MaxPods: 10}, PricePerHour: 0.10}
+}
+
+func largeCandidate() candidateInstance {
+	return candidateInstance{Type: "large", Spec: InstanceSpec{VCPU: 4, MemoryMega: 8192, MaxPods: 20}, PricePerHour: 0.20}
+}
+
+func TestPackPods(t *testing.T) {
+	t.Run("pods that fit together pack onto a single node", func(t *testing.T) {
+		demands := []podDemand{
+			{PodName: "a", CpuMillis: 1000, MemMega: 1024},
+			{PodName: "b", CpuMillis: 500, MemMega: 512},
+		}
+		nodes, unplaceable := packPods(demands, []candidateInstance{smallCandidate(), largeCandidate()})
+		if len(unplaceable) != 0 {
+			t.Fatalf("unplaceable = %v, want none", unplaceable)
+		}
+		if len(nodes) != 1 {
+			t.Fatalf("opened %d nodes, want 1", len(nodes))
+		}
+		if nodes[0].Candidate.Type != "small" {
+			t.Errorf("opened %s, want the cheaper small type", nodes[0].Candidate.Type)
+		}
+	})
+
+	t.Run("a pod too big for the smallest candidate opens the next size up", func(t *testing.T) {
+		demands := []podDemand{{PodName: "big", CpuMillis: 3000, MemMega: 1024}}
+		nodes, unplaceable := packPods(demands, []candidateInstance{smallCandidate(), largeCandidate()})
+		if len(unplaceable) != 0 {
+			t.Fatalf("unplaceable = %v, want none", unplaceable)
+		}
+		if len(nodes) != 1 || nodes[0].Candidate.Type != "large" {
+			t.Fatalf("nodes = %+v, want one large node", nodes)
+		}
+	})
+
+	t.Run("a pod bigger than every candidate is reported unplaceable, not dropped silently", func(t *testing.T) {
+		demands := []podDemand{{PodName: "huge", CpuMillis: 100000, MemMega: 1024}}
+		nodes, unplaceable := packPods(demands, []candidateInstance{smallCandidate(), largeCandidate()})
+		if len(nodes) != 0 {
+			t.Fatalf("nodes = %+v, want none opened", nodes)
+		}
+		if len(unplaceable) != 1 || unplaceable[0].PodName != "huge" {
+			t.Fatalf("unplaceable = %+v, want the huge pod", unplaceable)
+		}
+	})
+
+	t.Run("max pods per node forces a new node even with spare cpu/memory", func(t *testing.T) {
+		tiny := candidateInstance{Type: "tiny", Spec: InstanceSpec{VCPU: 100, MemoryMega: 100000, MaxPods: 1}, PricePerHour: 0.05}
+		demands := []podDemand{
+			{PodName: "a", CpuMillis: 100, MemMega: 100},
+			{PodName: "b", CpuMillis: 100, MemMega: 100},
+		}
+		nodes, unplaceable := packPods(demands, []candidateInstance{tiny})
+		if len(unplaceable) != 0 {
+			t.Fatalf("unplaceable = %v, want none", unplaceable)
+		}
+		if len(nodes) != 2 {
+			t.Fatalf("opened %d nodes, want 2 (one pod per max-pods=1 node)", len(nodes))
+		}
+	})
+
+	t.Run("best-fit prefers the tightest already-open node over a roomier one", func(t *testing.T) {
+		roomy := candidateInstance{Type: "roomy", Spec: InstanceSpec{VCPU: 4, MemoryMega: 4096, MaxPods: 10}, PricePerHour: 0.10}
+		// opener-1 and opener-2 can't share a node, so this opens two; c
+		// fits in the leftover space on both, and should land on whichever
+		// one it fits tighter (node 2, left with less spare capacity).
+		demands := []podDemand{
+			{PodName: "opener-1", CpuMillis: 3000, MemMega: 3000},
+			{PodName: "opener-2", CpuMillis: 3500, MemMega: 3500},
+			{PodName: "c", CpuMillis: 100, MemMega: 100},
+		}
+		nodes, unplaceable := packPods(demands, []candidateInstance{roomy})
+		if len(unplaceable) != 0 {
+			t.Fatalf("unplaceable = %v, want none", unplaceable)
+		}
+		if len(nodes) != 2 {
+			t.Fatalf("opened %d nodes, want 2", len(nodes))
+		}
+		if nodes[1].PodCount != 2 {
+			t.Errorf("the tighter-fit node hosts %d pods, want 2 (best fit should prefer it)", nodes[1].PodCount)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// spotCapacityLabels are the node labels used by common provisioners to mark
+// a node as backed by EC2 Spot capacity, in the order they are checked.
+var spotCapacityLabels = []string{
+	"karpenter.sh/capacity-type",
+	"eks.amazonaws.com/capacityType",
+	"node.kubernetes.io/lifecycle",
+}
+
+// isSpotNode reports whether node is labeled as running on Spot capacity by
+// any of the provisioners this tool knows about.
+func isSpotNode(node corev1.Node) bool {
+	for _, label := range spotCapacityLabels {
+		switch node.Labels[label] {
+		case "spot", "SPOT":
+			return true
+		}
+	}
+	return false
+}
+
+// spotPriceCache holds the most recently observed Spot price per
+// instance-type/AZ pair, as returned by DescribeSpotPriceHistory.
+type spotPriceCache struct {
+	// pricePerInstanceAZ is keyed by "<instanceType>/<availabilityZone>".
+	pricePerInstanceAZ map[string]float64
+}
+
+func newSpotPriceCache() *spotPriceCache {
+	return &spotPriceCache{pricePerInstanceAZ: map[string]float64{}}
+}
+
+func spotCacheKey(instanceType, az string) string {
+	return instanceType + "/" + az
+}
+
+// price returns the cached Spot price for instanceType in az, if any.
+func (c *spotPriceCache) price(instanceType, az string) (float64, bool) {
+	price, ok := c.pricePerInstanceAZ[spotCacheKey(instanceType, az)]
+	return price, ok
+}
+
+// loadSpotPriceHistory populates the cache from the AWS EC2
+// DescribeSpotPriceHistory API for the given region, keeping only the most
+// recent price per instance-type/AZ. Callers should treat a returned error as
+// non-fatal and fall back to the CLI-provided price map.
+func (c *spotPriceCache) loadSpotPriceHistory(ctx context.Context, region string) error {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return fmt.Errorf("loading AWS config: %w", err)
+	}
+	client := ec2.NewFromConfig(cfg)
+
+	paginator := ec2.NewDescribeSpotPriceHistoryPaginator(client, &ec2.DescribeSpotPriceHistoryInput{
+		ProductDescriptions: []string{string(types.RIProductDescriptionLinuxUnix)},
+	})
+
+	seenAt := map[string]int64{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("describing spot price history: %w", err)
+		}
+		for _, entry := range page.SpotPriceHistory {
+			if entry.InstanceType == "" || entry.AvailabilityZone == nil || entry.SpotPrice == nil {
+				continue
+			}
+			key := spotCacheKey(string(entry.InstanceType), *entry.AvailabilityZone)
+			var ts int64
+			if entry.Timestamp != nil {
+				ts = entry.Timestamp.Unix()
+			}
+			if existing, ok := seenAt[key]; ok && ts < existing {
+				continue
+			}
+			var price float64
+			if _, err := fmt.Sscanf(*entry.SpotPrice, "%f", &price); err != nil {
+				continue
+			}
+			seenAt[key] = ts
+			c.pricePerInstanceAZ[key] = price
+		}
+	}
+	log.Debugf("Loaded %v spot prices from AWS region %s.", len(c.pricePerInstanceAZ), region)
+	return nil
+}
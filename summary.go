@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// groupTotals accumulates the pod count and resolved Fargate cost for a
+// namespace, or a namespace/kind/name workload.
+type groupTotals struct {
+	Namespace string
+	Kind      string
+	Name      string
+	PodCount  int
+	CpuMillis int64
+	MemMega   int64
+	Price     float64
+}
+
+// printSummary prints an aggregated table according to cmd.GroupBy. "pod"
+// prints nothing further, since each pod was already logged as it was
+// resolved; "owner" and "namespace" print grouped tables instead.
+func (cmd *ReportCmd) printSummary(costs []podCost) {
+	switch cmd.GroupBy {
+	case "owner":
+		printOwnerSummary(costs)
+		printNamespaceSummary(costs)
+	case "namespace":
+		printNamespaceSummary(costs)
+	}
+}
+
+func printOwnerSummary(costs []podCost) {
+	groups := map[string]*groupTotals{}
+	var order []string
+	for _, c := range costs {
+		key := fmt.Sprintf("%s/%s/%s", c.Namespace, c.Owner.Kind, c.Owner.Name)
+		g, ok := groups[key]
+		if !ok {
+			g = &groupTotals{Namespace: c.Namespace, Kind: c.Owner.Kind, Name: c.Owner.Name}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.PodCount++
+		g.CpuMillis += c.CpuMillis
+		g.MemMega += c.MemMega
+		g.Price += c.Price
+	}
+	sort.Strings(order)
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE/KIND/NAME\tPODS\tCPU\tMEMORY\tHOURLY COST")
+	for _, key := range order {
+		g := groups[key]
+		fmt.Fprintf(w, "%s/%s/%s\t%d\t%v\t%vMi\t%v$\n", g.Namespace, g.Kind, g.Name, g.PodCount, float64(g.CpuMillis)/1000, g.MemMega, g.Price)
+	}
+	w.Flush()
+	log.Infof("Workload cost summary:\n%s", buf.String())
+}
+
+func printNamespaceSummary(costs []podCost) {
+	groups := map[string]*groupTotals{}
+	var order []string
+	for _, c := range costs {
+		g, ok := groups[c.Namespace]
+		if !ok {
+			g = &groupTotals{Namespace: c.Namespace}
+			groups[c.Namespace] = g
+			order = append(order, c.Namespace)
+		}
+		g.PodCount++
+		g.CpuMillis += c.CpuMillis
+		g.MemMega += c.MemMega
+		g.Price += c.Price
+	}
+	sort.Strings(order)
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tPODS\tCPU\tMEMORY\tHOURLY COST")
+	for _, ns := range order {
+		g := groups[ns]
+		fmt.Fprintf(w, "%s\t%d\t%v\t%vMi\t%v$\n", ns, g.PodCount, float64(g.CpuMillis)/1000, g.MemMega, g.Price)
+	}
+	w.Flush()
+	log.Infof("Namespace cost summary:\n%s", buf.String())
+}